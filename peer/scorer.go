@@ -0,0 +1,203 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// defaultDecay is applied to a peer's score before folding in each new
+// observation, so that old observations gradually lose influence relative to
+// more recent ones.
+const defaultDecay = 0.9
+
+// defaultMaxFailures is the number of failure credits a peer may accumulate
+// before it is temporarily blacklisted from SelectPeer.
+const defaultMaxFailures = 5
+
+// defaultCoolDown is how long a peer stays blacklisted after tripping
+// defaultMaxFailures.
+const defaultCoolDown = 30 * time.Second
+
+// FailureClass categorizes why a request to a peer failed, so that Scorer can
+// weight failures that indicate the peer is misbehaving (a bad proof, a hash
+// mismatch, an unparseable response) more heavily than ones that look like a
+// transient network hiccup.
+type FailureClass int
+
+const (
+	// FailureNetwork is used for timeouts, disconnects, and other errors that
+	// occurred before a response was received.
+	FailureNetwork FailureClass = iota
+	// FailureInvalidResponse is used when a response was received but failed
+	// validation, e.g. errInvalidRangeProof, errHashMismatch, or
+	// errUnmarshalResponse in statesync.
+	FailureInvalidResponse
+)
+
+// peerStats holds the exponentially-decayed score and failure bookkeeping for
+// a single peer.
+type peerStats struct {
+	score            float64
+	consecutiveFails int
+	blacklistedUntil time.Time
+
+	requests  uint64
+	successes uint64
+	failures  uint64
+}
+
+// Scorer tracks per-peer request success rate, latency, and throughput in
+// order to bias peer selection toward peers that have historically served
+// state sync requests quickly and correctly, and away from ones that time
+// out or return invalid responses.
+//
+// A Scorer is safe for concurrent use.
+type Scorer struct {
+	lock        sync.Mutex
+	decay       float64
+	maxFailures int
+	coolDown    time.Duration
+	stats       map[ids.ShortID]*peerStats
+}
+
+// NewScorer creates a Scorer that tracks the given candidate peers.
+func NewScorer(peers []ids.ShortID) *Scorer {
+	s := &Scorer{
+		decay:       defaultDecay,
+		maxFailures: defaultMaxFailures,
+		coolDown:    defaultCoolDown,
+		stats:       make(map[ids.ShortID]*peerStats, len(peers)),
+	}
+	for _, p := range peers {
+		s.stats[p] = &peerStats{}
+	}
+	return s
+}
+
+// statsFor returns the peerStats for [peer], creating it if this is the
+// first time the Scorer has seen it. Must be called with s.lock held.
+func (s *Scorer) statsFor(peer ids.ShortID) *peerStats {
+	st, ok := s.stats[peer]
+	if !ok {
+		st = &peerStats{}
+		s.stats[peer] = st
+	}
+	return st
+}
+
+// SelectPeer returns the highest-scoring peer that is not currently
+// blacklisted. If every known peer is blacklisted, it returns the peer whose
+// cool-down expires soonest, so that callers always make progress rather than
+// stalling. Returns the zero ids.ShortID if the Scorer was constructed with
+// no peers.
+func (s *Scorer) SelectPeer() ids.ShortID {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.stats) == 0 {
+		return ids.ShortID{}
+	}
+
+	now := time.Now()
+	var (
+		best, soonest         ids.ShortID
+		bestScore             = math.Inf(-1)
+		soonestUntil          time.Time
+		haveBest, haveSoonest bool
+	)
+	for peer, st := range s.stats {
+		if st.blacklistedUntil.After(now) {
+			if !haveSoonest || st.blacklistedUntil.Before(soonestUntil) {
+				soonest, soonestUntil, haveSoonest = peer, st.blacklistedUntil, true
+			}
+			continue
+		}
+		if !haveBest || st.score > bestScore {
+			best, bestScore, haveBest = peer, st.score, true
+		}
+	}
+
+	if haveBest {
+		return best
+	}
+	return soonest
+}
+
+// RecordSuccess updates [peer]'s score after a request that completed
+// successfully in [latency] and transferred [bytes] bytes of response. Faster
+// and larger responses increase the score more than slow, small ones, and any
+// prior blacklist is lifted.
+func (s *Scorer) RecordSuccess(peer ids.ShortID, latency time.Duration, bytes int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	st := s.statsFor(peer)
+	st.consecutiveFails = 0
+	st.blacklistedUntil = time.Time{}
+	st.requests++
+	st.successes++
+
+	throughput := float64(bytes) / math.Max(latency.Seconds(), 0.001)
+	st.score = st.score*s.decay + math.Log1p(throughput)
+}
+
+// RecordFailure updates [peer]'s score after a failed request of class
+// [errClass], blacklisting the peer for the configured cool-down once it
+// accumulates enough failure credits. A FailureInvalidResponse counts for
+// more credits than a FailureNetwork, since it indicates the peer returned
+// data that does not match what was asked for rather than simply being slow
+// or unreachable.
+func (s *Scorer) RecordFailure(peer ids.ShortID, errClass FailureClass) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	st := s.statsFor(peer)
+	st.requests++
+	st.failures++
+	st.score *= s.decay
+
+	credits := 1
+	if errClass == FailureInvalidResponse {
+		credits = 2
+	}
+	st.consecutiveFails += credits
+
+	if st.consecutiveFails >= s.maxFailures {
+		st.blacklistedUntil = time.Now().Add(s.coolDown)
+	}
+}
+
+// Stats is a point-in-time snapshot of a single peer's scoring state,
+// intended for metrics reporting.
+type Stats struct {
+	Score       float64
+	Requests    uint64
+	Successes   uint64
+	Failures    uint64
+	Blacklisted bool
+}
+
+// Snapshot returns a copy of the current per-peer stats, keyed by peer ID.
+func (s *Scorer) Snapshot() map[ids.ShortID]Stats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	out := make(map[ids.ShortID]Stats, len(s.stats))
+	for peer, st := range s.stats {
+		out[peer] = Stats{
+			Score:       st.score,
+			Requests:    st.requests,
+			Successes:   st.successes,
+			Failures:    st.failures,
+			Blacklisted: st.blacklistedUntil.After(now),
+		}
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestScorerSelectPeerPrefersHigherScore(t *testing.T) {
+	good := ids.GenerateTestShortID()
+	bad := ids.GenerateTestShortID()
+	s := NewScorer([]ids.ShortID{good, bad})
+
+	s.RecordSuccess(good, 10*time.Millisecond, 1<<20)
+	s.RecordFailure(bad, FailureNetwork)
+
+	if selected := s.SelectPeer(); selected != good {
+		t.Fatalf("expected SelectPeer to prefer the peer with successes, got %s", selected)
+	}
+}
+
+func TestScorerBlacklistsAfterMaxFailures(t *testing.T) {
+	bad := ids.GenerateTestShortID()
+	good := ids.GenerateTestShortID()
+	s := NewScorer([]ids.ShortID{bad, good})
+
+	for i := 0; i < defaultMaxFailures; i++ {
+		s.RecordFailure(bad, FailureNetwork)
+	}
+
+	snapshot := s.Snapshot()
+	if !snapshot[bad].Blacklisted {
+		t.Fatal("expected peer to be blacklisted after accumulating defaultMaxFailures failure credits")
+	}
+	if selected := s.SelectPeer(); selected != good {
+		t.Fatalf("expected SelectPeer to avoid the blacklisted peer, got %s", selected)
+	}
+}
+
+func TestScorerInvalidResponseCountsDoubleTowardBlacklist(t *testing.T) {
+	peer := ids.GenerateTestShortID()
+	s := NewScorer([]ids.ShortID{peer})
+
+	// FailureInvalidResponse counts for 2 credits, so it takes ceil(N/2)
+	// calls to accumulate defaultMaxFailures credits, vs. N for FailureNetwork.
+	iterations := (defaultMaxFailures + 1) / 2
+	for i := 0; i < iterations; i++ {
+		s.RecordFailure(peer, FailureInvalidResponse)
+	}
+
+	if !s.Snapshot()[peer].Blacklisted {
+		t.Fatalf("expected FailureInvalidResponse to blacklist the peer within %d calls", iterations)
+	}
+}
+
+func TestScorerRecordSuccessClearsBlacklist(t *testing.T) {
+	peer := ids.GenerateTestShortID()
+	s := NewScorer([]ids.ShortID{peer})
+
+	for i := 0; i < defaultMaxFailures; i++ {
+		s.RecordFailure(peer, FailureNetwork)
+	}
+	if !s.Snapshot()[peer].Blacklisted {
+		t.Fatal("expected peer to be blacklisted before RecordSuccess")
+	}
+
+	s.RecordSuccess(peer, time.Millisecond, 1024)
+	if s.Snapshot()[peer].Blacklisted {
+		t.Fatal("expected RecordSuccess to lift the blacklist")
+	}
+}
+
+func TestScorerSelectPeerReturnsSoonestWhenAllBlacklisted(t *testing.T) {
+	peerA := ids.GenerateTestShortID()
+	peerB := ids.GenerateTestShortID()
+	s := NewScorer([]ids.ShortID{peerA, peerB})
+
+	for i := 0; i < defaultMaxFailures; i++ {
+		s.RecordFailure(peerA, FailureNetwork)
+		s.RecordFailure(peerB, FailureNetwork)
+	}
+
+	selected := s.SelectPeer()
+	if selected != peerA && selected != peerB {
+		t.Fatalf("expected SelectPeer to still return one of the known peers when all are blacklisted, got %s", selected)
+	}
+}
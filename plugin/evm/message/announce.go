@@ -0,0 +1,55 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AnnouncedBlock is a (BlockHash, Height, StateRoot) tuple signed by a
+// trusted signer's staking key. A bootstrapping node that collects matching
+// AnnouncedBlocks from a quorum of its configured trusted signers can use the
+// tuple as its state sync pivot without running full consensus verification
+// on it, trading some trust for a faster cold start.
+type AnnouncedBlock struct {
+	BlockHash common.Hash `serialize:"true"`
+	Height    uint64      `serialize:"true"`
+	StateRoot common.Hash `serialize:"true"`
+	Signature []byte      `serialize:"true"`
+}
+
+func (a AnnouncedBlock) String() string {
+	return fmt.Sprintf("AnnouncedBlock(BlockHash=%s, Height=%d, StateRoot=%s)", a.BlockHash, a.Height, a.StateRoot)
+}
+
+// SigningBytes returns the canonical bytes a trusted signer signs over to
+// produce Signature, and that a verifier checks Signature against.
+func (a AnnouncedBlock) SigningBytes() []byte {
+	buf := make([]byte, 0, 2*common.HashLength+8)
+	buf = append(buf, a.BlockHash.Bytes()...)
+	buf = append(buf, a.StateRoot.Bytes()...)
+	var heightBytes [8]byte
+	binary.BigEndian.PutUint64(heightBytes[:], a.Height)
+	return append(buf, heightBytes[:]...)
+}
+
+// AnnouncementRequest asks a trusted signer for its current signed
+// AnnouncedBlock.
+type AnnouncementRequest struct{}
+
+func (AnnouncementRequest) String() string {
+	return "AnnouncementRequest"
+}
+
+// AnnouncementResponse is the answer to an AnnouncementRequest.
+type AnnouncementResponse struct {
+	Announcement AnnouncedBlock `serialize:"true"`
+}
+
+func (a AnnouncementResponse) String() string {
+	return fmt.Sprintf("AnnouncementResponse(%s)", a.Announcement)
+}
@@ -0,0 +1,67 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// codecVersion is the version this package's codec.Manager registers its
+// linearcodec.Codec under, and the version every Marshal/Unmarshal call
+// against that manager should use.
+const codecVersion = 0
+
+// maxMessageSize bounds the size of a single marshalled message.
+const maxMessageSize = 2 * 1024 * 1024 // 2 MiB
+
+// RegisterExtensionTypes registers the request/response types added on top of
+// the base state sync message set (LeafsRequest, BlockRequest, CodeRequest,
+// and their responses, registered where the base codec.Manager is built) so
+// that they can be marshalled and unmarshalled through the same
+// codec.Manager used by statesync.Client. It is exported so the base
+// codec.Manager construction site can fold these registrations in alongside
+// the base types; NewCodec below is this package's own call site, wiring the
+// registration in for callers that only need the types declared here.
+func RegisterExtensionTypes(c linearcodec.Codec) error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(MultiLeafsRequest{}),
+		c.RegisterType(MultiLeafsResponse{}),
+		c.RegisterType(HeadersRequest{}),
+		c.RegisterType(HeadersResponse{}),
+		c.RegisterType(ReceiptsRequest{}),
+		c.RegisterType(ReceiptsResponse{}),
+		c.RegisterType(TxStatusRequest{}),
+		c.RegisterType(TxStatusResponse{}),
+		c.RegisterType(AnnouncedBlock{}),
+		c.RegisterType(AnnouncementRequest{}),
+		c.RegisterType(AnnouncementResponse{}),
+		c.RegisterType(FlowControlParams{}),
+	)
+	return errs.Err
+}
+
+// NewCodec builds a codec.Manager with RegisterExtensionTypes actually
+// applied, registered under codecVersion. The base state sync message set
+// (LeafsRequest, BlockRequest, CodeRequest, and their responses) lives
+// outside this package and must be registered onto the same linearcodec.Codec
+// before RegisterCodec is called, wherever the production codec.Manager
+// handed to statesync.NewClient is assembled; NewCodec is this package's
+// self-contained manager for the types it owns, and the production
+// construction site should mirror this pattern rather than calling NewCodec
+// directly once the base types are folded in.
+func NewCodec() (codec.Manager, error) {
+	c := linearcodec.NewDefault()
+	if err := RegisterExtensionTypes(c); err != nil {
+		return nil, err
+	}
+
+	manager := codec.NewManager(maxMessageSize)
+	if err := manager.RegisterCodec(codecVersion, c); err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
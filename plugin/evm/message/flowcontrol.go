@@ -0,0 +1,19 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import "fmt"
+
+// FlowControlParams is the handshake extension a peer sends to advertise the
+// token-bucket parameters it grants the receiver, mirroring
+// statesync/flowcontrol.Params on the wire. A peer that never sends this has
+// no flow control applied to it by the client.
+type FlowControlParams struct {
+	BufferLimit  uint64 `serialize:"true"`
+	RechargeRate uint64 `serialize:"true"`
+}
+
+func (p FlowControlParams) String() string {
+	return fmt.Sprintf("FlowControlParams(BufferLimit=%d, RechargeRate=%d)", p.BufferLimit, p.RechargeRate)
+}
@@ -0,0 +1,22 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// LookupRequestHandler answers the transaction/receipt/header lookup
+// requests: HeadersRequest, ReceiptsRequest, and TxStatusRequest. It is
+// implemented server-side by a handler backed by the chain's block index and
+// tx pool, mirroring the existing per-message handlers for LeafsRequest,
+// BlockRequest, and CodeRequest. Each Handle method returns the marshalled
+// response bytes to send back to the requester.
+type LookupRequestHandler interface {
+	HandleHeadersRequest(ctx context.Context, nodeID ids.ShortID, requestID uint32, request HeadersRequest) ([]byte, error)
+	HandleReceiptsRequest(ctx context.Context, nodeID ids.ShortID, requestID uint32, request ReceiptsRequest) ([]byte, error)
+	HandleTxStatusRequest(ctx context.Context, nodeID ids.ShortID, requestID uint32, request TxStatusRequest) ([]byte, error)
+}
@@ -0,0 +1,115 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// HeadersRequest asks for up to [Amount] headers starting at [Hash], skipping
+// [Skip] blocks between each and walking towards the genesis if [Reverse] is set.
+// It mirrors BlockRequest but avoids the cost of transferring full block
+// bodies when a caller only needs headers.
+type HeadersRequest struct {
+	Hash    common.Hash `serialize:"true"`
+	Amount  uint16      `serialize:"true"`
+	Skip    uint16      `serialize:"true"`
+	Reverse bool        `serialize:"true"`
+}
+
+func (h HeadersRequest) String() string {
+	return fmt.Sprintf("HeadersRequest(Hash=%s, Amount=%d, Skip=%d, Reverse=%v)", h.Hash, h.Amount, h.Skip, h.Reverse)
+}
+
+// HeadersResponse is the answer to a HeadersRequest. Headers are RLP-encoded
+// types.Header, in the same order as requested.
+type HeadersResponse struct {
+	Headers [][]byte `serialize:"true"`
+}
+
+func (h HeadersResponse) String() string {
+	return fmt.Sprintf("HeadersResponse(Headers=%d)", len(h.Headers))
+}
+
+// ReceiptsRequest asks for the full receipt list of every block in
+// [BlockHashes].
+type ReceiptsRequest struct {
+	BlockHashes []common.Hash `serialize:"true"`
+}
+
+func (r ReceiptsRequest) String() string {
+	return fmt.Sprintf("ReceiptsRequest(BlockHashes=%d)", len(r.BlockHashes))
+}
+
+// ReceiptsResponse is the answer to a ReceiptsRequest. Receipts is the
+// RLP-encoded types.Receipts for the corresponding block in
+// ReceiptsRequest.BlockHashes, in the same order as requested.
+type ReceiptsResponse struct {
+	Receipts [][]byte `serialize:"true"`
+}
+
+func (r ReceiptsResponse) String() string {
+	return fmt.Sprintf("ReceiptsResponse(Receipts=%d)", len(r.Receipts))
+}
+
+// TxStatusCode describes where, if anywhere, a transaction has been observed.
+type TxStatusCode uint8
+
+const (
+	// TxStatusUnknown means the responder has never seen the transaction.
+	TxStatusUnknown TxStatusCode = iota
+	// TxStatusQueued means the transaction is known but not yet executable,
+	// e.g. waiting on a lower nonce.
+	TxStatusQueued
+	// TxStatusPending means the transaction is in the mempool and executable.
+	TxStatusPending
+	// TxStatusIncluded means the transaction has been accepted in a block.
+	TxStatusIncluded
+)
+
+func (s TxStatusCode) String() string {
+	switch s {
+	case TxStatusUnknown:
+		return "Unknown"
+	case TxStatusQueued:
+		return "Queued"
+	case TxStatusPending:
+		return "Pending"
+	case TxStatusIncluded:
+		return "Included"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(s))
+	}
+}
+
+// TxStatus is the per-transaction result of a TxStatusRequest. BlockHash,
+// BlockNumber, and Index are only meaningful when Status is TxStatusIncluded.
+type TxStatus struct {
+	Status      TxStatusCode `serialize:"true"`
+	BlockHash   common.Hash  `serialize:"true"`
+	BlockNumber uint64       `serialize:"true"`
+	Index       uint32       `serialize:"true"`
+}
+
+// TxStatusRequest asks for the status of every hash in [TxHashes], analogous
+// to LES's GetTxStatusMsg.
+type TxStatusRequest struct {
+	TxHashes []common.Hash `serialize:"true"`
+}
+
+func (t TxStatusRequest) String() string {
+	return fmt.Sprintf("TxStatusRequest(TxHashes=%d)", len(t.TxHashes))
+}
+
+// TxStatusResponse is the answer to a TxStatusRequest. Statuses is in the
+// same order as TxStatusRequest.TxHashes.
+type TxStatusResponse struct {
+	Statuses []TxStatus `serialize:"true"`
+}
+
+func (t TxStatusResponse) String() string {
+	return fmt.Sprintf("TxStatusResponse(Statuses=%d)", len(t.Statuses))
+}
@@ -0,0 +1,48 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LeafsSubRequest is a single (Start, End, Limit) sub-range within a
+// MultiLeafsRequest. It mirrors LeafsRequest but omits Root, since every
+// sub-range in a MultiLeafsRequest shares the root of the request as a whole.
+type LeafsSubRequest struct {
+	Start []byte `serialize:"true"`
+	End   []byte `serialize:"true"`
+	Limit uint16 `serialize:"true"`
+}
+
+// MultiLeafsRequest packs several leaf-range sub-requests that all apply to
+// the same state [Root] into a single wire request, so that a syncer pulling
+// many small, potentially overlapping tries (e.g. one per contract storage
+// trie) pays one request/response round trip instead of one per range.
+type MultiLeafsRequest struct {
+	Root        common.Hash       `serialize:"true"`
+	SubRequests []LeafsSubRequest `serialize:"true"`
+}
+
+func (m MultiLeafsRequest) String() string {
+	return fmt.Sprintf("MultiLeafsRequest(Root=%s, SubRequests=%d)", m.Root, len(m.SubRequests))
+}
+
+// MultiLeafsResponse is the answer to a MultiLeafsRequest. Keys and Vals are
+// parallel slices-of-slices, one inner slice per sub-request, in the same
+// order as MultiLeafsRequest.SubRequests. The merkle proof nodes covering
+// every sub-range are returned once, in ProofKeys/ProofVals, rather than
+// once per sub-range, since overlapping small tries commonly share nodes.
+type MultiLeafsResponse struct {
+	Keys      [][][]byte `serialize:"true"`
+	Vals      [][][]byte `serialize:"true"`
+	ProofKeys [][]byte   `serialize:"true"`
+	ProofVals [][]byte   `serialize:"true"`
+}
+
+func (m MultiLeafsResponse) String() string {
+	return fmt.Sprintf("MultiLeafsResponse(SubResponses=%d, ProofNodes=%d)", len(m.Keys), len(m.ProofKeys))
+}
@@ -0,0 +1,157 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ava-labs/coreth/plugin/evm/message"
+)
+
+var (
+	errNoTrustedSigners = errors.New("no trusted signers configured")
+	errQuorumNotReached = errors.New("did not reach quorum of matching signed announcements")
+)
+
+// SignatureVerifier checks that [sig] is [signer]'s signature over [msg],
+// e.g. message.AnnouncedBlock.SigningBytes(). Implementations typically
+// verify against the signer's staking key as tracked by the validator set.
+type SignatureVerifier func(signer ids.ShortID, msg []byte, sig []byte) bool
+
+// announcementVote is a single trusted signer's verified AnnouncedBlock.
+type announcementVote struct {
+	announcement message.AnnouncedBlock
+	signer       ids.ShortID
+}
+
+// announcementKey identifies a distinct (BlockHash, Height, StateRoot) tuple
+// for tallying purposes. It must cover every field of the tuple a trusted
+// signer signs over - keying on BlockHash alone would let a single signer
+// that reuses an honest BlockHash but a different Height/StateRoot merge its
+// vote into the honest tally.
+type announcementKey struct {
+	blockHash common.Hash
+	height    uint64
+	stateRoot common.Hash
+}
+
+// announcementTally tracks the distinct signers that have voted for one
+// announcementKey.
+type announcementTally struct {
+	announcement message.AnnouncedBlock
+	signers      map[ids.ShortID]bool
+}
+
+// tallyAnnouncementVote folds [vote] into [tallies] and returns the
+// announcement once it has votes from at least [quorumThreshold] distinct
+// signers, or nil if no tuple has reached quorum yet. It is a pure function
+// of its arguments so the quorum-merge logic can be tested without any
+// network or codec plumbing.
+func tallyAnnouncementVote(tallies map[announcementKey]*announcementTally, vote announcementVote, quorumThreshold int) *message.AnnouncedBlock {
+	key := announcementKey{
+		blockHash: vote.announcement.BlockHash,
+		height:    vote.announcement.Height,
+		stateRoot: vote.announcement.StateRoot,
+	}
+
+	t, ok := tallies[key]
+	if !ok {
+		t = &announcementTally{announcement: vote.announcement, signers: make(map[ids.ShortID]bool)}
+		tallies[key] = t
+	}
+	t.signers[vote.signer] = true
+
+	if len(t.signers) < quorumThreshold {
+		return nil
+	}
+	announcement := t.announcement
+	return &announcement
+}
+
+// VerifyAnnouncement polls every peer in [trustedSigners] for its signed
+// AnnouncedBlock and returns the tuple as soon as at least [quorumThreshold]
+// of them agree on the same (BlockHash, Height, StateRoot). This lets a
+// bootstrapping node accept a state sync pivot on the strength of a quorum of
+// trusted signatures instead of running full consensus verification on it -
+// it shortcuts pivot selection, it does not replace verification of the
+// chain that follows. Returns errQuorumNotReached if every trusted signer has
+// responded (or failed) without a quorum forming.
+func (c *client) VerifyAnnouncement(ctx context.Context) (*message.AnnouncedBlock, error) {
+	if len(c.trustedSigners) == 0 {
+		return nil, errNoTrustedSigners
+	}
+
+	votes := make(chan announcementVote, len(c.trustedSigners))
+	var wg sync.WaitGroup
+	for _, signer := range c.trustedSigners {
+		signer := signer
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.fetchAnnouncement(ctx, signer, votes)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(votes)
+	}()
+
+	tallies := make(map[announcementKey]*announcementTally)
+	responded := 0
+
+	for {
+		select {
+		case vote, ok := <-votes:
+			if !ok {
+				return nil, fmt.Errorf("%w: %d/%d trusted signers responded, needed %d matching", errQuorumNotReached, responded, len(c.trustedSigners), c.quorumThreshold)
+			}
+			responded++
+
+			if announcement := tallyAnnouncementVote(tallies, vote, c.quorumThreshold); announcement != nil {
+				return announcement, nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// fetchAnnouncement requests and verifies [signer]'s AnnouncedBlock,
+// publishing it onto [votes] only if the signature checks out.
+func (c *client) fetchAnnouncement(ctx context.Context, signer ids.ShortID, votes chan<- announcementVote) {
+	data, err := c.getFromNode(signer, message.AnnouncementRequest{}, c.maxAttempts, c.maxRetryDelay, c.parseAnnouncement)
+	if err != nil {
+		log.Info("could not fetch announcement from trusted signer", "nodeID", signer, "err", err)
+		return
+	}
+
+	announcement := data.(message.AnnouncedBlock)
+	if !c.verifySignerSig(signer, announcement.SigningBytes(), announcement.Signature) {
+		log.Info("dropping announcement with invalid signature", "nodeID", signer)
+		return
+	}
+
+	select {
+	case votes <- announcementVote{announcement: announcement, signer: signer}:
+	case <-ctx.Done():
+	}
+}
+
+// parseAnnouncement validates given object as message.AnnouncementResponse
+// assumes reqIntf is of type message.AnnouncementRequest
+// returns a non-nil error if the request should be retried
+func (c *client) parseAnnouncement(reqIntf message.Request, data []byte) (interface{}, error) {
+	var response message.AnnouncementResponse
+	if _, err := c.codec.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
+	}
+	return response.Announcement, nil
+}
@@ -0,0 +1,72 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/coreth/plugin/evm/message"
+)
+
+func TestTallyAnnouncementVoteReachesQuorum(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	root := common.HexToHash("0x02")
+	signerA := ids.GenerateTestShortID()
+	signerB := ids.GenerateTestShortID()
+
+	tallies := make(map[announcementKey]*announcementTally)
+	voteA := announcementVote{
+		announcement: message.AnnouncedBlock{BlockHash: hash, Height: 100, StateRoot: root},
+		signer:       signerA,
+	}
+	voteB := announcementVote{
+		announcement: message.AnnouncedBlock{BlockHash: hash, Height: 100, StateRoot: root},
+		signer:       signerB,
+	}
+
+	if announcement := tallyAnnouncementVote(tallies, voteA, 2); announcement != nil {
+		t.Fatalf("expected no quorum after one vote, got %+v", announcement)
+	}
+	announcement := tallyAnnouncementVote(tallies, voteB, 2)
+	if announcement == nil {
+		t.Fatal("expected quorum after two matching votes")
+	}
+	if announcement.BlockHash != hash || announcement.Height != 100 || announcement.StateRoot != root {
+		t.Fatalf("unexpected announcement returned: %+v", announcement)
+	}
+}
+
+func TestTallyAnnouncementVoteRejectsMismatchedTuple(t *testing.T) {
+	// A faulty signer reuses the honest BlockHash but signs a different
+	// Height/StateRoot. Its vote must not count toward the honest tally.
+	hash := common.HexToHash("0x01")
+	honestRoot := common.HexToHash("0x02")
+	badRoot := common.HexToHash("0x03")
+	honestSigner := ids.GenerateTestShortID()
+	badSigner := ids.GenerateTestShortID()
+
+	tallies := make(map[announcementKey]*announcementTally)
+	honestVote := announcementVote{
+		announcement: message.AnnouncedBlock{BlockHash: hash, Height: 100, StateRoot: honestRoot},
+		signer:       honestSigner,
+	}
+	badVote := announcementVote{
+		announcement: message.AnnouncedBlock{BlockHash: hash, Height: 101, StateRoot: badRoot},
+		signer:       badSigner,
+	}
+
+	if announcement := tallyAnnouncementVote(tallies, badVote, 2); announcement != nil {
+		t.Fatalf("expected no quorum from a single bad vote, got %+v", announcement)
+	}
+	if announcement := tallyAnnouncementVote(tallies, honestVote, 2); announcement != nil {
+		t.Fatalf("bad vote must not have merged into the honest tally, got %+v", announcement)
+	}
+
+	if len(tallies) != 2 {
+		t.Fatalf("expected mismatched tuples to occupy distinct tally buckets, got %d", len(tallies))
+	}
+}
@@ -0,0 +1,149 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/coreth/plugin/evm/message"
+)
+
+// defaultMaxInFlightPerPeer bounds how many GetLeafs requests AsyncGetLeafs
+// keeps outstanding against a single peer at once.
+const defaultMaxInFlightPerPeer = 4
+
+// LeafsResult is delivered on the channel returned by AsyncGetLeafs for each
+// entry in the requested slice. Results are published in completion order,
+// which does not necessarily match the order the requests were given in, so
+// [Index] identifies the originating request for callers that need to
+// reassemble ranges in order.
+type LeafsResult struct {
+	Index    int
+	Request  message.LeafsRequest
+	Response *message.LeafsResponse
+	Err      error
+}
+
+// leafsJob pairs a leafs request with its position in the caller-supplied slice.
+type leafsJob struct {
+	index   int
+	request message.LeafsRequest
+}
+
+// AsyncGetLeafs pipelines [requests] across the configured peer set instead of
+// resolving them one at a time like GetLeafs. Requests are pulled from a
+// shared queue by a bounded pool of workers per peer, so a leaf syncer with
+// many ranges is no longer bottlenecked on one round trip per range. Each
+// range is retried independently of the others, so a slow or failing range
+// does not hold up the rest of the pipeline.
+//
+// The returned channel receives exactly one LeafsResult per entry in
+// [requests] and is closed once all of them have completed (successfully or
+// not). AsyncGetLeafs itself only returns an error if [requests] is empty or
+// the pipeline could not be started; per-range failures surface through
+// LeafsResult.Err.
+func (c *client) AsyncGetLeafs(ctx context.Context, requests []message.LeafsRequest) (<-chan LeafsResult, error) {
+	if len(requests) == 0 {
+		return nil, errors.New("no leafs requests provided")
+	}
+	return pipelineLeafs(ctx, requests, c.leafsWorkerFns()), nil
+}
+
+// pipelineLeafs is the concurrency core of AsyncGetLeafs: it fans [requests]
+// out across [workerFns] and fans the results back in. It is split out from
+// AsyncGetLeafs so it can be tested against fake workerFns without a network.
+func pipelineLeafs(ctx context.Context, requests []message.LeafsRequest, workerFns []leafsWorkerFn) <-chan LeafsResult {
+	jobs := make(chan leafsJob)
+	results := make(chan LeafsResult, len(requests))
+
+	var wg sync.WaitGroup
+	for _, workerFn := range workerFns {
+		workerFn := workerFn
+		for i := 0; i < defaultMaxInFlightPerPeer; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				runLeafsWorker(ctx, workerFn, jobs, results)
+			}()
+		}
+	}
+
+	// The producer is counted in wg too: it may still be publishing
+	// synthesized results after every worker has exited on cancellation.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for i, req := range requests {
+			select {
+			case jobs <- leafsJob{index: i, request: req}:
+			case <-ctx.Done():
+				// Synthesize a result for every request this and later
+				// iterations never got to dispatch, so the channel still
+				// delivers exactly one LeafsResult per entry in requests.
+				for j := i; j < len(requests); j++ {
+					results <- LeafsResult{Index: j, Request: requests[j], Err: ctx.Err()}
+				}
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// leafsWorkerFn resolves a single LeafsRequest, typically pinned to one peer.
+type leafsWorkerFn func(message.LeafsRequest) (*message.LeafsResponse, error)
+
+// leafsWorkerFns returns one worker function per configured state sync node,
+// each of which always targets that node. If no nodes are pinned, it returns
+// a single worker function that falls back to RequestAny for every request,
+// matching the behavior of the synchronous GetLeafs.
+func (c *client) leafsWorkerFns() []leafsWorkerFn {
+	if len(c.stateSyncNodes) == 0 {
+		return []leafsWorkerFn{c.GetLeafs}
+	}
+
+	fns := make([]leafsWorkerFn, len(c.stateSyncNodes))
+	for i, nodeID := range c.stateSyncNodes {
+		nodeID := nodeID
+		fns[i] = func(request message.LeafsRequest) (*message.LeafsResponse, error) {
+			data, err := c.getFromNode(nodeID, request, c.maxAttempts, c.maxRetryDelay, c.parseLeafsResponse)
+			if err != nil {
+				return nil, err
+			}
+			response, ok := data.(message.LeafsResponse)
+			if !ok {
+				return nil, fmt.Errorf("received unexpected type in response, expected: %T", response)
+			}
+			return &response, nil
+		}
+	}
+	return fns
+}
+
+// runLeafsWorker repeatedly pulls jobs off [jobs] and resolves them with
+// [workerFn], publishing exactly one LeafsResult per job onto [results].
+func runLeafsWorker(ctx context.Context, workerFn leafsWorkerFn, jobs <-chan leafsJob, results chan<- LeafsResult) {
+	for {
+		select {
+		case job, ok := <-jobs:
+			if !ok {
+				return
+			}
+			response, err := workerFn(job.request)
+			results <- LeafsResult{Index: job.index, Request: job.request, Response: response, Err: err}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
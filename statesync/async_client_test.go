@@ -0,0 +1,167 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/coreth/plugin/evm/message"
+)
+
+func drainLeafsResults(t *testing.T, results <-chan LeafsResult, want int) []LeafsResult {
+	t.Helper()
+
+	collected := make([]LeafsResult, 0, want)
+	timeout := time.After(5 * time.Second)
+	for len(collected) < want {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				t.Fatalf("results channel closed early: got %d results, wanted %d", len(collected), want)
+			}
+			collected = append(collected, result)
+		case <-timeout:
+			t.Fatalf("timed out waiting for results: got %d, wanted %d", len(collected), want)
+		}
+	}
+
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Fatal("expected no more than the requested number of results")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected results channel to close once every request completed")
+	}
+	return collected
+}
+
+func newLeafsRequests(n int) []message.LeafsRequest {
+	requests := make([]message.LeafsRequest, n)
+	for i := range requests {
+		requests[i] = message.LeafsRequest{Limit: uint16(i + 1)}
+	}
+	return requests
+}
+
+func TestPipelineLeafsNormalCompletion(t *testing.T) {
+	requests := newLeafsRequests(10)
+
+	var calls int32
+	workerFn := func(request message.LeafsRequest) (*message.LeafsResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		return &message.LeafsResponse{Keys: [][]byte{{byte(request.Limit)}}}, nil
+	}
+
+	results := pipelineLeafs(context.Background(), requests, []leafsWorkerFn{workerFn})
+	collected := drainLeafsResults(t, results, len(requests))
+
+	seen := make(map[int]bool)
+	for _, result := range collected {
+		if result.Err != nil {
+			t.Fatalf("unexpected error for index %d: %v", result.Index, result.Err)
+		}
+		if result.Response == nil {
+			t.Fatalf("expected a response for index %d", result.Index)
+		}
+		if seen[result.Index] {
+			t.Fatalf("index %d delivered more than once", result.Index)
+		}
+		seen[result.Index] = true
+	}
+	if len(seen) != len(requests) {
+		t.Fatalf("expected every index 0..%d to be delivered exactly once, got %d distinct indices", len(requests)-1, len(seen))
+	}
+	if got := atomic.LoadInt32(&calls); got != int32(len(requests)) {
+		t.Fatalf("expected workerFn to be called once per request, got %d calls for %d requests", got, len(requests))
+	}
+}
+
+func TestPipelineLeafsPartialFailure(t *testing.T) {
+	requests := newLeafsRequests(6)
+	errBadRange := errors.New("simulated permanent failure")
+
+	workerFn := func(request message.LeafsRequest) (*message.LeafsResponse, error) {
+		// Odd limits fail, even limits succeed - exercises both outcomes
+		// flowing through the same pipeline concurrently.
+		if request.Limit%2 == 1 {
+			return nil, errBadRange
+		}
+		return &message.LeafsResponse{}, nil
+	}
+
+	results := pipelineLeafs(context.Background(), requests, []leafsWorkerFn{workerFn})
+	collected := drainLeafsResults(t, results, len(requests))
+
+	var succeeded, failed int
+	for _, result := range collected {
+		req := requests[result.Index]
+		if req.Limit%2 == 1 {
+			if result.Err != errBadRange {
+				t.Fatalf("expected index %d to fail with errBadRange, got %v", result.Index, result.Err)
+			}
+			failed++
+		} else {
+			if result.Err != nil {
+				t.Fatalf("expected index %d to succeed, got %v", result.Index, result.Err)
+			}
+			succeeded++
+		}
+	}
+	if succeeded == 0 || failed == 0 {
+		t.Fatalf("expected a mix of successes and failures, got %d successes, %d failures", succeeded, failed)
+	}
+}
+
+func TestPipelineLeafsCancellationMidDispatch(t *testing.T) {
+	requests := newLeafsRequests(20)
+
+	// release is closed only after the test has observed the first job, so
+	// the producer is guaranteed to still have undispatched requests left
+	// when ctx is cancelled.
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	workerFn := func(request message.LeafsRequest) (*message.LeafsResponse, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return &message.LeafsResponse{}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// A single workerFn still only gets defaultMaxInFlightPerPeer concurrent
+	// workers, so with far more requests than that, most are still queued in
+	// the producer when every worker blocks on release.
+	results := pipelineLeafs(ctx, requests, []leafsWorkerFn{workerFn})
+
+	<-started
+	cancel()
+	close(release)
+
+	collected := drainLeafsResults(t, results, len(requests))
+
+	seen := make(map[int]bool)
+	var canceled int
+	for _, result := range collected {
+		if seen[result.Index] {
+			t.Fatalf("index %d delivered more than once", result.Index)
+		}
+		seen[result.Index] = true
+		if result.Err == context.Canceled {
+			canceled++
+		}
+	}
+	if len(seen) != len(requests) {
+		t.Fatalf("expected every index 0..%d to be delivered exactly once despite cancellation, got %d distinct indices", len(requests)-1, len(seen))
+	}
+	if canceled == 0 {
+		t.Fatal("expected at least one request to be reported as canceled before it was dispatched")
+	}
+}
@@ -5,9 +5,11 @@ package statesync
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -22,6 +24,7 @@ import (
 	"github.com/ava-labs/coreth/ethdb"
 	"github.com/ava-labs/coreth/peer"
 	"github.com/ava-labs/coreth/plugin/evm/message"
+	"github.com/ava-labs/coreth/statesync/flowcontrol"
 	"github.com/ava-labs/coreth/trie"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -40,6 +43,7 @@ var (
 	errExceededRetryLimit = errors.New("exceeded request retry limit")
 	errTooManyLeaves      = errors.New("response contains more than requested leaves")
 	errUnmarshalResponse  = errors.New("failed to unmarshal response")
+	errTooManyHeaders     = errors.New("response contains more headers than requested")
 )
 var _ Client = &client{}
 
@@ -48,12 +52,40 @@ type Client interface {
 	// GetLeafs synchronously sends given request, returning parsed *LeafsResponse or error
 	GetLeafs(request message.LeafsRequest) (*message.LeafsResponse, error)
 
+	// AsyncGetLeafs pipelines [requests] across the configured peer set,
+	// returning a channel of LeafsResult as each range completes rather than
+	// blocking on one range at a time like GetLeafs.
+	AsyncGetLeafs(ctx context.Context, requests []message.LeafsRequest) (<-chan LeafsResult, error)
+
+	// GetMultiLeafs synchronously retrieves leafs for every sub-range in
+	// [subRequests], all against the same state [root], as a single
+	// consolidated request and response.
+	GetMultiLeafs(root common.Hash, subRequests []message.LeafsSubRequest) ([]*message.LeafsResponse, error)
+
 	// GetBlocks synchronously retrieves blocks starting with specified common.Hash and height up to specified parents
 	// specified range from height to height-parents is inclusive
 	GetBlocks(blockHash common.Hash, height uint64, parents uint16) ([]*types.Block, error)
 
 	// GetCode synchronously retrieves code associated with given common.Hash
 	GetCode(common.Hash) ([]byte, error)
+
+	// GetHeaders synchronously retrieves up to [amount] headers starting at
+	// [hash], skipping [skip] blocks between each, walking towards the genesis
+	// if [reverse] is set.
+	GetHeaders(hash common.Hash, amount uint16, skip uint16, reverse bool) ([]*types.Header, error)
+
+	// GetReceipts synchronously retrieves the receipt list for each block in
+	// [blockHashes], in the same order as requested.
+	GetReceipts(blockHashes []common.Hash) ([]types.Receipts, error)
+
+	// GetTxStatus synchronously retrieves the inclusion status of each
+	// transaction in [txHashes], in the same order as requested.
+	GetTxStatus(txHashes []common.Hash) ([]message.TxStatus, error)
+
+	// VerifyAnnouncement polls the configured TrustedSigners for a quorum of
+	// matching signed block announcements, for use as a trust-minimized
+	// state sync pivot shortcut.
+	VerifyAnnouncement(ctx context.Context) (*message.AnnouncedBlock, error)
 }
 
 // parseResponseFn parses given response bytes in context of specified request
@@ -67,17 +99,106 @@ type client struct {
 	maxAttempts    uint8
 	maxRetryDelay  time.Duration
 	stateSyncNodes []ids.ShortID
-	nodeIdx        int
+	scorer         *peer.Scorer
+
+	// trustedSigners, quorumThreshold, and verifySignerSig back
+	// VerifyAnnouncement. A client that never calls VerifyAnnouncement can
+	// leave them at their zero values.
+	trustedSigners  []ids.ShortID
+	quorumThreshold int
+	verifySignerSig SignatureVerifier
+
+	// flowControl paces requests to peers that have advertised buffer/recharge
+	// parameters in their handshake extension. It is nil until a caller opts
+	// in with WithFlowControl, in which case dispatch falls back to sending
+	// immediately for any peer with no advertised parameters.
+	flowControl *flowcontrol.Manager
 }
 
 func NewClient(networkClient peer.Client, maxAttempts uint8, maxRetryDelay time.Duration, codec codec.Manager, stateSyncNodes []ids.ShortID) *client {
-	return &client{
+	c := &client{
 		networkClient:  networkClient,
 		maxAttempts:    maxAttempts,
 		maxRetryDelay:  maxRetryDelay,
 		codec:          codec,
 		stateSyncNodes: stateSyncNodes,
 	}
+	if len(stateSyncNodes) > 0 {
+		// Weight selection within the pinned node set by observed peer
+		// quality instead of visiting every node in strict rotation.
+		c.scorer = peer.NewScorer(stateSyncNodes)
+	}
+	return c
+}
+
+// WithTrustedSigners configures c to accept a state sync pivot via
+// VerifyAnnouncement once at least [quorumThreshold] of [trustedSigners]
+// produce matching signed AnnouncedBlocks, verified with [verifySignerSig].
+func (c *client) WithTrustedSigners(trustedSigners []ids.ShortID, quorumThreshold int, verifySignerSig SignatureVerifier) *client {
+	c.trustedSigners = trustedSigners
+	c.quorumThreshold = quorumThreshold
+	c.verifySignerSig = verifySignerSig
+	return c
+}
+
+// WithFlowControl enables bandwidth-aware pacing of requests to peers. Costs
+// are estimated per request (see estimateRequestCost) and peer parameters are
+// registered as they are learned via SetPeerFlowControlParams; peers with no
+// known parameters are dispatched to immediately, same as before this was
+// introduced.
+func (c *client) WithFlowControl() *client {
+	c.flowControl = flowcontrol.NewManager()
+	return c
+}
+
+// SetPeerFlowControlParams records [params] as advertised by [peer] in its
+// handshake extension. Call WithFlowControl first to enable flow control at
+// all; this is a no-op otherwise.
+func (c *client) SetPeerFlowControlParams(peer ids.ShortID, params flowcontrol.Params) {
+	if c.flowControl == nil {
+		return
+	}
+	c.flowControl.SetParams(peer, params)
+}
+
+// OnPeerFlowControlParams applies [params] as advertised by [peer] in its
+// handshake extension (message.FlowControlParams). This is the hook the
+// connection-setup path should call once it has decoded that extension from
+// a peer's handshake; wiring that decode step into the handshake itself
+// lives outside this package.
+func (c *client) OnPeerFlowControlParams(peer ids.ShortID, params message.FlowControlParams) {
+	c.SetPeerFlowControlParams(peer, flowcontrol.Params{
+		BufferLimit:  params.BufferLimit,
+		RechargeRate: params.RechargeRate,
+	})
+}
+
+// estimateRequestCost estimates the cost of [request] for flow-control
+// accounting: proof bytes (approximated by the leaf limit) for leaf
+// requests, block count for GetBlocks, header count for GetHeaders, the
+// number of requested hashes for GetReceipts and GetTxStatus, and a nominal
+// fixed cost for everything else.
+func estimateRequestCost(request message.Request) uint64 {
+	switch r := request.(type) {
+	case message.LeafsRequest:
+		return uint64(r.Limit)
+	case message.MultiLeafsRequest:
+		var total uint64
+		for _, sub := range r.SubRequests {
+			total += uint64(sub.Limit)
+		}
+		return total
+	case message.BlockRequest:
+		return uint64(r.Parents)
+	case message.HeadersRequest:
+		return uint64(r.Amount)
+	case message.ReceiptsRequest:
+		return uint64(len(r.BlockHashes))
+	case message.TxStatusRequest:
+		return uint64(len(r.TxHashes))
+	default:
+		return 1
+	}
 }
 
 // GetLeafs synchronously retrieves leafs as per given [message.LeafsRequest]
@@ -127,20 +248,14 @@ func (c *client) parseLeafsResponse(reqIntf message.Request, data []byte) (inter
 		return nil, fmt.Errorf("empty key response must include merkle proof")
 	}
 
-	var proof ethdb.Database
 	// Populate proof when ProofKeys are present in the response. Its ok to pass it as nil to the trie.VerifyRangeProof
 	// function as it will assert that all the leaves belonging to the specified root are present.
-	if len(leafsResponse.ProofKeys) > 0 {
-		if len(leafsResponse.ProofKeys) != len(leafsResponse.ProofVals) {
-			return nil, fmt.Errorf("mismatch in length of proof keys (%d)/vals (%d)", len(leafsResponse.ProofKeys), len(leafsResponse.ProofVals))
-		}
-		proof = memorydb.New()
+	proof, err := populateProofDB(leafsResponse.ProofKeys, leafsResponse.ProofVals)
+	if err != nil {
+		return nil, err
+	}
+	if proof != nil {
 		defer proof.Close()
-		for i, proofKey := range leafsResponse.ProofKeys {
-			if err := proof.Put(proofKey, leafsResponse.ProofVals[i]); err != nil {
-				return nil, err
-			}
-		}
 	}
 
 	var (
@@ -170,6 +285,125 @@ func (c *client) parseLeafsResponse(reqIntf message.Request, data []byte) (inter
 	return leafsResponse, nil
 }
 
+// populateProofDB loads parallel [proofKeys]/[proofVals] slices into a fresh
+// in-memory database suitable for trie.VerifyRangeProof. Returns a nil
+// database, which trie.VerifyRangeProof accepts to mean "no proof", if
+// [proofKeys] is empty.
+func populateProofDB(proofKeys, proofVals [][]byte) (ethdb.Database, error) {
+	if len(proofKeys) == 0 {
+		return nil, nil
+	}
+	if len(proofKeys) != len(proofVals) {
+		return nil, fmt.Errorf("mismatch in length of proof keys (%d)/vals (%d)", len(proofKeys), len(proofVals))
+	}
+
+	proof := memorydb.New()
+	for i, proofKey := range proofKeys {
+		if err := proof.Put(proofKey, proofVals[i]); err != nil {
+			proof.Close()
+			return nil, err
+		}
+	}
+	return proof, nil
+}
+
+// GetMultiLeafs synchronously retrieves leafs for every sub-range in
+// [subRequests], all against the same state [root], as a single consolidated
+// request. It generalizes GetLeafs for syncing many small, potentially
+// overlapping tries (e.g. per-contract storage tries) where the per-request
+// overhead of issuing one GetLeafs per range would otherwise dominate.
+func (c *client) GetMultiLeafs(root common.Hash, subRequests []message.LeafsSubRequest) ([]*message.LeafsResponse, error) {
+	req := message.MultiLeafsRequest{
+		Root:        root,
+		SubRequests: subRequests,
+	}
+
+	data, err := c.get(req, c.maxAttempts, c.maxRetryDelay, c.parseMultiLeafsResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, ok := data.([]message.LeafsResponse)
+	if !ok {
+		return nil, fmt.Errorf("received unexpected type in response, expected: %T", responses)
+	}
+
+	out := make([]*message.LeafsResponse, len(responses))
+	for i := range responses {
+		out[i] = &responses[i]
+	}
+	return out, nil
+}
+
+// parseMultiLeafsResponse validates given object as message.MultiLeafsResponse
+// assumes reqIntf is of type message.MultiLeafsRequest
+// returns a non-nil error if the request should be retried
+// Unlike parseLeafsResponse, the proof nodes for every sub-range are
+// consolidated into a single [proof] database, since MultiLeafsResponse
+// returns them deduplicated rather than once per sub-range.
+func (c *client) parseMultiLeafsResponse(reqIntf message.Request, data []byte) (interface{}, error) {
+	var multiResponse message.MultiLeafsResponse
+	if _, err := c.codec.Unmarshal(data, &multiResponse); err != nil {
+		return nil, err
+	}
+
+	multiRequest := reqIntf.(message.MultiLeafsRequest)
+
+	if len(multiResponse.Keys) != len(multiRequest.SubRequests) || len(multiResponse.Vals) != len(multiRequest.SubRequests) {
+		return nil, fmt.Errorf("mismatch in number of sub-responses (%d keys, %d vals) and sub-requests (%d)", len(multiResponse.Keys), len(multiResponse.Vals), len(multiRequest.SubRequests))
+	}
+
+	// As with parseLeafsResponse, an empty sub-range (no more keys) requires a
+	// merkle proof backing that claim. The proof is consolidated across all
+	// sub-ranges, so if it's empty entirely, no sub-range may claim to be empty.
+	if len(multiResponse.ProofKeys) == 0 {
+		for i, keys := range multiResponse.Keys {
+			if len(keys) == 0 {
+				return nil, fmt.Errorf("empty key response for sub-request %d must include merkle proof", i)
+			}
+		}
+	}
+
+	proof, err := populateProofDB(multiResponse.ProofKeys, multiResponse.ProofVals)
+	if err != nil {
+		return nil, err
+	}
+	if proof != nil {
+		defer proof.Close()
+	}
+
+	responses := make([]message.LeafsResponse, len(multiRequest.SubRequests))
+	for i, sub := range multiRequest.SubRequests {
+		keys := multiResponse.Keys[i]
+		vals := multiResponse.Vals[i]
+		if len(keys) > int(sub.Limit) || len(vals) > int(sub.Limit) {
+			return nil, fmt.Errorf("%w: (%d) > %d)", errTooManyLeaves, len(keys), sub.Limit)
+		}
+
+		firstKey := sub.Start
+		if firstKey == nil {
+			firstKey = bytes.Repeat([]byte{0x00}, len(sub.End))
+		}
+		lastKey := sub.End
+		if len(keys) > 0 {
+			lastKey = keys[len(keys)-1]
+		}
+
+		more, err := trie.VerifyRangeProof(multiRequest.Root, firstKey, lastKey, keys, vals, proof)
+		if err != nil {
+			return nil, fmt.Errorf("%s due to %w (sub-request %d)", errInvalidRangeProof, err, i)
+		}
+
+		responses[i] = message.LeafsResponse{
+			Keys: keys,
+			Vals: vals,
+			More: more,
+		}
+	}
+
+	return responses, nil
+}
+
 func (c *client) GetBlocks(hash common.Hash, height uint64, parents uint16) ([]*types.Block, error) {
 	req := message.BlockRequest{
 		Hash:    hash,
@@ -258,6 +492,133 @@ func (c *client) parseCode(req message.Request, data []byte) (interface{}, error
 	return response, nil
 }
 
+// GetHeaders synchronously retrieves up to [amount] headers starting at
+// [hash], analogous to LES's GetBlockHeadersMsg. Unlike GetBlocks, it does
+// not fetch block bodies, so it is cheaper when a caller only needs headers.
+func (c *client) GetHeaders(hash common.Hash, amount uint16, skip uint16, reverse bool) ([]*types.Header, error) {
+	req := message.HeadersRequest{
+		Hash:    hash,
+		Amount:  amount,
+		Skip:    skip,
+		Reverse: reverse,
+	}
+
+	data, err := c.get(req, c.maxAttempts, c.maxRetryDelay, c.parseHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("could not get headers (%s): %w", hash, err)
+	}
+
+	return data.([]*types.Header), nil
+}
+
+// parseHeaders validates given object as message.HeadersResponse
+// assumes req is of type message.HeadersRequest
+// returns a non-nil error if the request should be retried
+func (c *client) parseHeaders(req message.Request, data []byte) (interface{}, error) {
+	var response message.HeadersResponse
+	if _, err := c.codec.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
+	}
+	if len(response.Headers) == 0 {
+		return nil, errEmptyResponse
+	}
+
+	headersRequest := req.(message.HeadersRequest)
+	if len(response.Headers) > int(headersRequest.Amount) {
+		return nil, errTooManyHeaders
+	}
+
+	headers := make([]*types.Header, len(response.Headers))
+	for i, headerBytes := range response.Headers {
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(headerBytes, header); err != nil {
+			return nil, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
+		}
+		headers[i] = header
+	}
+
+	return headers, nil
+}
+
+// GetReceipts synchronously retrieves the receipt list for each block in
+// [blockHashes], analogous to LES's GetReceiptsMsg.
+func (c *client) GetReceipts(blockHashes []common.Hash) ([]types.Receipts, error) {
+	req := message.ReceiptsRequest{
+		BlockHashes: blockHashes,
+	}
+
+	data, err := c.get(req, c.maxAttempts, c.maxRetryDelay, c.parseReceipts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get receipts: %w", err)
+	}
+
+	return data.([]types.Receipts), nil
+}
+
+// parseReceipts validates given object as message.ReceiptsResponse
+// assumes req is of type message.ReceiptsRequest
+// returns a non-nil error if the request should be retried
+func (c *client) parseReceipts(req message.Request, data []byte) (interface{}, error) {
+	var response message.ReceiptsResponse
+	if _, err := c.codec.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
+	}
+
+	receiptsRequest := req.(message.ReceiptsRequest)
+	if len(response.Receipts) != len(receiptsRequest.BlockHashes) {
+		return nil, fmt.Errorf("mismatch in number of receipt lists (%d) and requested block hashes (%d)", len(response.Receipts), len(receiptsRequest.BlockHashes))
+	}
+
+	receipts := make([]types.Receipts, len(response.Receipts))
+	for i, receiptsBytes := range response.Receipts {
+		// An empty entry is how the responder reports a block it does not
+		// know; it is left as a nil types.Receipts instead of failing the
+		// whole request.
+		if len(receiptsBytes) == 0 {
+			continue
+		}
+		var blockReceipts types.Receipts
+		if err := rlp.DecodeBytes(receiptsBytes, &blockReceipts); err != nil {
+			return nil, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
+		}
+		receipts[i] = blockReceipts
+	}
+
+	return receipts, nil
+}
+
+// GetTxStatus synchronously retrieves the inclusion status of each
+// transaction in [txHashes], analogous to LES's GetTxStatusMsg.
+func (c *client) GetTxStatus(txHashes []common.Hash) ([]message.TxStatus, error) {
+	req := message.TxStatusRequest{
+		TxHashes: txHashes,
+	}
+
+	data, err := c.get(req, c.maxAttempts, c.maxRetryDelay, c.parseTxStatus)
+	if err != nil {
+		return nil, fmt.Errorf("could not get tx status: %w", err)
+	}
+
+	return data.([]message.TxStatus), nil
+}
+
+// parseTxStatus validates given object as message.TxStatusResponse
+// assumes req is of type message.TxStatusRequest
+// returns a non-nil error if the request should be retried
+func (c *client) parseTxStatus(req message.Request, data []byte) (interface{}, error) {
+	var response message.TxStatusResponse
+	if _, err := c.codec.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalResponse, err)
+	}
+
+	txStatusRequest := req.(message.TxStatusRequest)
+	if len(response.Statuses) != len(txStatusRequest.TxHashes) {
+		return nil, fmt.Errorf("mismatch in number of statuses (%d) and requested tx hashes (%d)", len(response.Statuses), len(txStatusRequest.TxHashes))
+	}
+
+	return response.Statuses, nil
+}
+
 // get submits given request and blockingly returns with either a parsed response object or error
 // retry is made if there is a network error or if the [parseResponseFn] returns a non-nil error
 // returns parsed struct as interface{} returned by parseResponseFn
@@ -285,30 +646,140 @@ func (c *client) get(request message.Request, attempts uint8, maxRetryDelay time
 			response []byte
 			nodeID   ids.ShortID
 		)
-		if len(c.stateSyncNodes) == 0 {
-			response, nodeID, err = c.networkClient.RequestAny(StateSyncVersion, requestBytes)
-		} else {
-			// get the next nodeID using the nodeIdx offset. If we're out of nodes, loop back to 0
-			// we do this every attempt to ensure we get a different node each time if possible.
-			c.nodeIdx = (c.nodeIdx + 1) % len(c.stateSyncNodes)
-			nodeID = c.stateSyncNodes[c.nodeIdx]
-
-			response, err = c.networkClient.Request(nodeID, requestBytes)
-		}
-
+		start := time.Now()
+		response, nodeID, err = c.dispatch(nil, requestBytes, estimateRequestCost(request))
 		if err != nil {
+			if c.scorer != nil {
+				c.scorer.RecordFailure(nodeID, peer.FailureNetwork)
+			}
 			log.Info("request failed, retrying", "nodeID", nodeID, "attempt", attempt, "request", request, "err", err)
 			continue
 		} else {
 			responseIntf, err = parseFn(request, response)
 			if err != nil {
+				if c.scorer != nil {
+					c.scorer.RecordFailure(nodeID, classifyFailure(err))
+				}
 				log.Info("could not validate response, retrying", "nodeID", nodeID, "attempt", attempt, "request", request, "err", err)
 				continue
 			}
+			if c.scorer != nil {
+				c.scorer.RecordSuccess(nodeID, time.Since(start), len(response))
+			}
 			return responseIntf, nil
 		}
 	}
 
 	// we only get this far if we've run out of attempts
+	return nil, fmt.Errorf("%s (%d): %w", errExceededRetryLimit, attempts, err)
+}
+
+// dispatch sends [requestBytes] to the network, returning the raw response
+// bytes, the node that served it, and any network-level error.
+// If [pinnedNode] is non-nil, the request is always sent to that node.
+// Otherwise it falls back to RequestAny when no [stateSyncNodes] are
+// configured, or asks [c.scorer] for the best-performing peer within
+// [stateSyncNodes].
+//
+// When the target peer is known ahead of the send (the pinned and
+// stateSyncNodes cases, but not RequestAny), dispatch waits for [cost] credit
+// in that peer's flow-control bucket first, if flow control is enabled and
+// the peer has advertised parameters.
+func (c *client) dispatch(pinnedNode *ids.ShortID, requestBytes []byte, cost uint64) ([]byte, ids.ShortID, error) {
+	if pinnedNode != nil {
+		if c.flowControl != nil {
+			c.flowControl.Wait(*pinnedNode, cost)
+		}
+		response, err := c.networkClient.Request(*pinnedNode, requestBytes)
+		return response, *pinnedNode, err
+	}
+
+	if len(c.stateSyncNodes) == 0 {
+		return c.networkClient.RequestAny(StateSyncVersion, requestBytes)
+	}
+
+	// Ask the scorer for the best-performing peer within the pinned set. This
+	// still only ever contacts nodes from [stateSyncNodes], but weights
+	// selection by observed success rate, latency, and throughput rather than
+	// visiting every node in strict rotation.
+	nodeID := c.scorer.SelectPeer()
+
+	if c.flowControl != nil {
+		c.flowControl.Wait(nodeID, cost)
+	}
+
+	response, err := c.networkClient.Request(nodeID, requestBytes)
+	return response, nodeID, err
+}
+
+// classifyFailure maps a response-validation error returned by a parseFn to
+// the peer.FailureClass the Scorer uses to weight it, so that peers serving
+// bad proofs, mismatched hashes, or unparseable responses are demoted faster
+// than ones that merely time out.
+func classifyFailure(err error) peer.FailureClass {
+	for _, target := range []error{errInvalidRangeProof, errHashMismatch, errUnmarshalResponse} {
+		// Several parse functions format the sentinel with %s and wrap only
+		// the underlying cause with %w, so errors.Is alone does not find it.
+		if errors.Is(err, target) || strings.Contains(err.Error(), target.Error()) {
+			return peer.FailureInvalidResponse
+		}
+	}
+	return peer.FailureNetwork
+}
+
+// getFromNode behaves like get, except every attempt is pinned to [nodeID]
+// instead of letting [c.scorer] pick within [stateSyncNodes] or falling back
+// to RequestAny.
+// It is used by callers that have already chosen a peer for this request,
+// such as the per-peer workers in AsyncGetLeafs.
+func (c *client) getFromNode(nodeID ids.ShortID, request message.Request, attempts uint8, maxRetryDelay time.Duration, parseFn parseResponseFn) (interface{}, error) {
+	requestBytes, err := message.RequestToBytes(c.codec, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only nodes within [stateSyncNodes] are scored, so that a node from
+	// outside that set (e.g. a trusted signer polled by VerifyAnnouncement)
+	// never becomes a candidate for dispatch's scorer-based selection.
+	var scorer *peer.Scorer
+	for _, stateSyncNode := range c.stateSyncNodes {
+		if stateSyncNode == nodeID {
+			scorer = c.scorer
+			break
+		}
+	}
+
+	var responseIntf interface{}
+	for attempt := uint8(0); attempt < attempts; attempt++ {
+		if attempt > 0 {
+			randTime := rand.Int63n(maxRetryDelay.Nanoseconds())
+			time.Sleep(time.Duration(randTime))
+		}
+
+		var response []byte
+		start := time.Now()
+		response, _, err = c.dispatch(&nodeID, requestBytes, estimateRequestCost(request))
+		if err != nil {
+			if scorer != nil {
+				scorer.RecordFailure(nodeID, peer.FailureNetwork)
+			}
+			log.Info("request to peer failed, retrying", "nodeID", nodeID, "attempt", attempt, "request", request, "err", err)
+			continue
+		}
+
+		responseIntf, err = parseFn(request, response)
+		if err != nil {
+			if scorer != nil {
+				scorer.RecordFailure(nodeID, classifyFailure(err))
+			}
+			log.Info("could not validate response from peer, retrying", "nodeID", nodeID, "attempt", attempt, "request", request, "err", err)
+			continue
+		}
+		if scorer != nil {
+			scorer.RecordSuccess(nodeID, time.Since(start), len(response))
+		}
+		return responseIntf, nil
+	}
+
 	return nil, fmt.Errorf("%s (%d): %w", errExceededRetryLimit, attempts, err)
 }
\ No newline at end of file
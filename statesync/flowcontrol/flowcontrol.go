@@ -0,0 +1,94 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package flowcontrol implements a client-side shadow token bucket per peer,
+// modeled on the go-ethereum LES flowcontrol package. Each peer advertises a
+// buffer limit and recharge rate in a handshake extension; the client mirrors
+// that buffer locally and waits for sufficient credit before dispatching a
+// request, instead of firing requests immediately and relying on retries
+// when a slow peer falls behind.
+package flowcontrol
+
+import (
+	"sync"
+	"time"
+)
+
+// Params are a single peer's advertised flow-control parameters.
+type Params struct {
+	// BufferLimit is the maximum cost credit the peer grants the client.
+	BufferLimit uint64
+	// RechargeRate is how much cost credit recharges per second, up to
+	// BufferLimit. A RechargeRate of 0 is treated as "unconstrained": Wait
+	// returns immediately without consuming the buffer.
+	RechargeRate uint64
+}
+
+// Bucket is a client-side shadow copy of one peer's token bucket: it mirrors
+// the buffer the peer is assumed to maintain server-side, so the client can
+// pace its own requests instead of sending them all immediately and
+// discovering the peer is overloaded only after it times out.
+//
+// A Bucket is safe for concurrent use.
+type Bucket struct {
+	mu       sync.Mutex
+	params   Params
+	balance  float64
+	lastSync time.Time
+}
+
+// NewBucket creates a Bucket initialized to a full buffer under [params].
+func NewBucket(params Params) *Bucket {
+	return &Bucket{
+		params:   params,
+		balance:  float64(params.BufferLimit),
+		lastSync: time.Now(),
+	}
+}
+
+// recharge folds in credit accumulated since the last call. Must be called
+// with b.mu held.
+func (b *Bucket) recharge() {
+	now := time.Now()
+	elapsed := now.Sub(b.lastSync).Seconds()
+	b.lastSync = now
+	if elapsed <= 0 || b.params.RechargeRate == 0 {
+		return
+	}
+
+	b.balance += elapsed * float64(b.params.RechargeRate)
+	if limit := float64(b.params.BufferLimit); b.balance > limit {
+		b.balance = limit
+	}
+}
+
+// Wait blocks until at least [cost] credit is available in the bucket, then
+// deducts it. If the bucket's RechargeRate is 0 (no flow-control params known
+// for this peer), Wait returns immediately. A [cost] above the bucket's
+// BufferLimit is capped to BufferLimit before waiting, since balance never
+// recharges past BufferLimit (see recharge) and waiting for the full
+// uncapped cost would never succeed.
+func (b *Bucket) Wait(cost uint64) {
+	for {
+		b.mu.Lock()
+		if b.params.RechargeRate == 0 {
+			b.mu.Unlock()
+			return
+		}
+		if limit := b.params.BufferLimit; cost > limit {
+			cost = limit
+		}
+
+		b.recharge()
+		if b.balance >= float64(cost) {
+			b.balance -= float64(cost)
+			b.mu.Unlock()
+			return
+		}
+
+		deficit := float64(cost) - b.balance
+		waitFor := time.Duration(deficit / float64(b.params.RechargeRate) * float64(time.Second))
+		b.mu.Unlock()
+		time.Sleep(waitFor)
+	}
+}
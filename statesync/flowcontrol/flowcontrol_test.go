@@ -0,0 +1,66 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package flowcontrol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketWaitDeductsAvailableBalance(t *testing.T) {
+	b := NewBucket(Params{BufferLimit: 100, RechargeRate: 10})
+
+	done := make(chan struct{})
+	go func() {
+		b.Wait(40)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return immediately for a cost within the full buffer")
+	}
+
+	b.mu.Lock()
+	balance := b.balance
+	b.mu.Unlock()
+	if balance > 60.5 || balance < 59.5 {
+		t.Fatalf("expected balance near 60 after deducting cost 40 from a full 100 buffer, got %v", balance)
+	}
+}
+
+func TestBucketWaitUnconstrainedWhenNoRechargeRate(t *testing.T) {
+	b := NewBucket(Params{})
+
+	done := make(chan struct{})
+	go func() {
+		b.Wait(1_000_000)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait should return immediately when RechargeRate is 0")
+	}
+}
+
+func TestBucketWaitCostAboveBufferLimitDoesNotLivelock(t *testing.T) {
+	// recharge() clamps balance to BufferLimit, so a cost above BufferLimit
+	// must be capped rather than waited on forever.
+	b := NewBucket(Params{BufferLimit: 10, RechargeRate: 1000})
+
+	done := make(chan struct{})
+	go func() {
+		b.Wait(1_000_000)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait livelocked on a cost exceeding BufferLimit")
+	}
+}
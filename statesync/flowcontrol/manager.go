@@ -0,0 +1,49 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package flowcontrol
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Manager tracks a Bucket per peer, created the first time that peer
+// advertises its Params via SetParams. Peers that have never advertised
+// Params have no Bucket, so Wait for them returns immediately - the existing
+// RequestAny/legacy dispatch behavior keeps working unchanged.
+//
+// A Manager is safe for concurrent use.
+type Manager struct {
+	mu      sync.Mutex
+	buckets map[ids.ShortID]*Bucket
+}
+
+// NewManager creates an empty Manager.
+func NewManager() *Manager {
+	return &Manager{
+		buckets: make(map[ids.ShortID]*Bucket),
+	}
+}
+
+// SetParams records [params] as advertised by [peer] in its handshake
+// extension, replacing any previously advertised params for that peer.
+func (m *Manager) SetParams(peer ids.ShortID, params Params) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buckets[peer] = NewBucket(params)
+}
+
+// Wait blocks until [peer] has enough credit for a request of estimated
+// [cost], then deducts it. If [peer] has no known Params, Wait returns
+// immediately.
+func (m *Manager) Wait(peer ids.ShortID, cost uint64) {
+	m.mu.Lock()
+	bucket, ok := m.buckets[peer]
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+	bucket.Wait(cost)
+}
@@ -0,0 +1,154 @@
+// (c) 2021-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ava-labs/coreth/plugin/evm/message"
+)
+
+// ChainReader is the subset of a node's chain state that lookupRequestHandler
+// needs to answer HeadersRequest, ReceiptsRequest, and TxStatusRequest.
+type ChainReader interface {
+	// GetHeader returns the header identified by [hash], or nil if unknown.
+	GetHeader(hash common.Hash) *types.Header
+	// GetHeaderByNumber returns the canonical header at [number], or nil if
+	// unknown.
+	GetHeaderByNumber(number uint64) *types.Header
+	// GetReceipts returns the receipts for the block identified by [hash], or
+	// nil if unknown.
+	GetReceipts(hash common.Hash) types.Receipts
+	// TxLookup returns where, if anywhere, [txHash] has been observed: its
+	// inclusion point if accepted into a block, or its mempool status
+	// otherwise.
+	TxLookup(txHash common.Hash) message.TxStatus
+}
+
+var _ message.LookupRequestHandler = (*lookupRequestHandler)(nil)
+
+// lookupRequestHandler answers HeadersRequest, ReceiptsRequest, and
+// TxStatusRequest against a ChainReader, mirroring the existing per-message
+// handlers for LeafsRequest, BlockRequest, and CodeRequest.
+type lookupRequestHandler struct {
+	chain        ChainReader
+	codec        codec.Manager
+	codecVersion uint16
+}
+
+// NewLookupRequestHandler creates a message.LookupRequestHandler that
+// answers requests against [chain], marshalling responses with [codec] at
+// [codecVersion].
+func NewLookupRequestHandler(chain ChainReader, codec codec.Manager, codecVersion uint16) message.LookupRequestHandler {
+	return &lookupRequestHandler{chain: chain, codec: codec, codecVersion: codecVersion}
+}
+
+// HandleHeadersRequest answers a HeadersRequest by walking the chain from
+// request.Hash, stepping request.Skip+1 blocks at a time, towards the
+// genesis if request.Reverse is set, stopping early if it runs off the known
+// chain before collecting request.Amount headers.
+func (h *lookupRequestHandler) HandleHeadersRequest(ctx context.Context, nodeID ids.ShortID, requestID uint32, request message.HeadersRequest) ([]byte, error) {
+	headers := make([][]byte, 0, request.Amount)
+
+	header := h.chain.GetHeader(request.Hash)
+	for i := uint16(0); i < request.Amount && header != nil; i++ {
+		encoded, err := rlp.EncodeToBytes(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode header %s: %w", header.Hash(), err)
+		}
+		headers = append(headers, encoded)
+
+		step := uint64(request.Skip) + 1
+		number := header.Number.Uint64()
+		if request.Reverse {
+			if number < step {
+				break
+			}
+			number -= step
+		} else {
+			number += step
+		}
+		header = h.chain.GetHeaderByNumber(number)
+	}
+
+	response := message.HeadersResponse{Headers: headers}
+	responseBytes, err := h.codec.Marshal(h.codecVersion, response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal HeadersResponse: %w", err)
+	}
+	log.Debug("handled HeadersRequest", "nodeID", nodeID, "requestID", requestID, "headers", len(headers))
+	return responseBytes, nil
+}
+
+// HandleReceiptsRequest answers a ReceiptsRequest. A block hash unknown to
+// the chain contributes a nil entry rather than failing the whole request,
+// so callers can still use the receipts they did get back.
+func (h *lookupRequestHandler) HandleReceiptsRequest(ctx context.Context, nodeID ids.ShortID, requestID uint32, request message.ReceiptsRequest) ([]byte, error) {
+	receiptsList := make([][]byte, 0, len(request.BlockHashes))
+	for _, hash := range request.BlockHashes {
+		receipts := h.chain.GetReceipts(hash)
+		if receipts == nil {
+			receiptsList = append(receiptsList, nil)
+			continue
+		}
+		encoded, err := rlp.EncodeToBytes(receipts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode receipts for block %s: %w", hash, err)
+		}
+		receiptsList = append(receiptsList, encoded)
+	}
+
+	response := message.ReceiptsResponse{Receipts: receiptsList}
+	responseBytes, err := h.codec.Marshal(h.codecVersion, response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ReceiptsResponse: %w", err)
+	}
+	log.Debug("handled ReceiptsRequest", "nodeID", nodeID, "requestID", requestID, "blocks", len(request.BlockHashes))
+	return responseBytes, nil
+}
+
+// HandleTxStatusRequest answers a TxStatusRequest. A transaction hash the
+// chain has never observed is reported as message.TxStatusUnknown rather
+// than failing the whole request.
+func (h *lookupRequestHandler) HandleTxStatusRequest(ctx context.Context, nodeID ids.ShortID, requestID uint32, request message.TxStatusRequest) ([]byte, error) {
+	statuses := make([]message.TxStatus, 0, len(request.TxHashes))
+	for _, txHash := range request.TxHashes {
+		statuses = append(statuses, h.chain.TxLookup(txHash))
+	}
+
+	response := message.TxStatusResponse{Statuses: statuses}
+	responseBytes, err := h.codec.Marshal(h.codecVersion, response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal TxStatusResponse: %w", err)
+	}
+	log.Debug("handled TxStatusRequest", "nodeID", nodeID, "requestID", requestID, "txs", len(request.TxHashes))
+	return responseBytes, nil
+}
+
+// HandleLookupRequest dispatches [request] to the method on [handler]
+// matching its concrete type, returning the marshalled response bytes to
+// send back to [nodeID]. This is the entry point the network's inbound
+// message router calls once it has decoded a HeadersRequest, ReceiptsRequest,
+// or TxStatusRequest off the wire; wiring that decode step into the router
+// itself lives outside this package.
+func HandleLookupRequest(ctx context.Context, handler message.LookupRequestHandler, nodeID ids.ShortID, requestID uint32, request message.Request) ([]byte, error) {
+	switch request := request.(type) {
+	case message.HeadersRequest:
+		return handler.HandleHeadersRequest(ctx, nodeID, requestID, request)
+	case message.ReceiptsRequest:
+		return handler.HandleReceiptsRequest(ctx, nodeID, requestID, request)
+	case message.TxStatusRequest:
+		return handler.HandleTxStatusRequest(ctx, nodeID, requestID, request)
+	default:
+		return nil, fmt.Errorf("unexpected request type for lookup handler: %T", request)
+	}
+}